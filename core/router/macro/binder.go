@@ -0,0 +1,30 @@
+package macro
+
+// ParamBinderFunc converts a param value, already validated by its
+// macro's evaluator, into the typed Go value a handler argument of the
+// matching type should receive, e.g. float64 for "float64" or time.Time
+// for "date".
+type ParamBinderFunc func(paramValue string) (interface{}, bool)
+
+// paramBinders maps a macro's Indent() to the ParamBinderFunc that
+// produces a handler argument for it. Populated by each macro type's
+// own init func (see float.go, datetime.go) rather than registered
+// here, so a type's binder lives next to the Parse* helper it wraps.
+var paramBinders = map[string]ParamBinderFunc{}
+
+// RegisterParamBinder associates indent with binder. A ParamBinder
+// dispatch table -- in the hero/binding layer, which isn't part of
+// this package (and isn't present in this checkout) -- looks up a
+// handler argument's matching macro by indent and calls ParamBinderFor
+// to convert the raw param value instead of re-parsing the string
+// itself. Registering the same indent twice replaces the previous
+// binder.
+func RegisterParamBinder(indent string, binder ParamBinderFunc) {
+	paramBinders[indent] = binder
+}
+
+// ParamBinderFor returns the binder registered for indent, if any.
+func ParamBinderFor(indent string) (ParamBinderFunc, bool) {
+	binder, ok := paramBinders[indent]
+	return binder, ok
+}
@@ -0,0 +1,33 @@
+package macro
+
+import "testing"
+
+func TestRegisterAndLookupParamBinder(t *testing.T) {
+	const indent = "binder_test_indent"
+
+	if _, ok := ParamBinderFor(indent); ok {
+		t.Fatalf("expected no binder registered yet for %q", indent)
+	}
+
+	RegisterParamBinder(indent, func(paramValue string) (interface{}, bool) {
+		return paramValue + "-bound", true
+	})
+
+	binder, ok := ParamBinderFor(indent)
+	if !ok {
+		t.Fatalf("expected a binder registered for %q", indent)
+	}
+	v, ok := binder("x")
+	if !ok || v != "x-bound" {
+		t.Fatalf(`binder("x") = %v, %v, want "x-bound", true`, v, ok)
+	}
+
+	// Registering again replaces the previous binder.
+	RegisterParamBinder(indent, func(paramValue string) (interface{}, bool) {
+		return paramValue + "-rebound", true
+	})
+	binder, _ = ParamBinderFor(indent)
+	if v, _ := binder("x"); v != "x-rebound" {
+		t.Fatalf(`expected re-registering %q to replace the binder, got %v`, indent, v)
+	}
+}
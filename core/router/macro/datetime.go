@@ -0,0 +1,320 @@
+package macro
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// buildTimeMacro builds a Date/Time/DateTime-style macro type whose
+// layout is fixed at construction time and captured in the closures
+// below, rather than living in state shared across the app. An earlier
+// version of this file instead kept a single "layout" evaluator func
+// per type, switched via a map keyed by the shared *Macro singleton --
+// that made a custom layout on any one route silently change parsing
+// for every other route using the same type, since the map has no
+// notion of "route". There's no such func anymore: a route that needs
+// a non-RFC3339 layout registers its own macro type built with
+// NewTimeMacro instead, so the layout can never leak across routes.
+func buildTimeMacro(indent, alias, layout string) *Macro {
+	parse := func(paramValue string) (time.Time, bool) {
+		t, err := time.Parse(layout, paramValue)
+		return t, err == nil
+	}
+
+	return NewMacro(indent, alias, false, false, func(paramValue string) bool {
+		_, ok := parse(paramValue)
+		return ok
+	}).
+		// checks if the param value, parsed as a time, is strictly
+		// before 't'.
+		RegisterFunc("before", func(t string) EvaluatorFunc {
+			boundary, ok := parse(t)
+			return func(paramValue string) bool {
+				if !ok {
+					return false
+				}
+				v, vok := parse(paramValue)
+				return vok && v.Before(boundary)
+			}
+		}).
+		// checks if the param value, parsed as a time, is strictly
+		// after 't'.
+		RegisterFunc("after", func(t string) EvaluatorFunc {
+			boundary, ok := parse(t)
+			return func(paramValue string) bool {
+				if !ok {
+					return false
+				}
+				v, vok := parse(paramValue)
+				return vok && v.After(boundary)
+			}
+		}).
+		// checks if the param value, parsed as a time, is between
+		// 'a' and 'b', including 'a' and 'b'.
+		RegisterFunc("between", func(a, b string) EvaluatorFunc {
+			start, sok := parse(a)
+			end, eok := parse(b)
+			return func(paramValue string) bool {
+				if !sok || !eok {
+					return false
+				}
+				v, vok := parse(paramValue)
+				return vok && !v.Before(start) && !v.After(end)
+			}
+		}).
+		// checks if the param value, parsed as a time, falls on one
+		// of the given weekdays, e.g. weekday("mon", "tue").
+		RegisterFunc("weekday", func(days ...string) EvaluatorFunc {
+			return func(paramValue string) bool {
+				v, ok := parse(paramValue)
+				if !ok {
+					return false
+				}
+				weekday := strings.ToLower(v.Weekday().String())[:3]
+				for _, day := range days {
+					if len(day) >= 3 && strings.ToLower(day[:3]) == weekday {
+						return true
+					}
+				}
+				return false
+			}
+		})
+}
+
+// NewTimeMacro builds an additional Date/Time/DateTime-style macro type
+// using layout instead of time.RFC3339, for routes that need a
+// different format, e.g.:
+//
+//	europeanDate := macro.NewTimeMacro("date_eu", "", "02-01-2006")
+//	app.Macros().Register("date_eu", "", false, false, europeanDate.Evaluator)
+//
+// Its "before"/"after"/"between"/"weekday" funcs always parse against
+// layout, so distinct instances never interfere with each other or
+// with Date/Time/DateTime. Registering through Macros.Register as above
+// carries over only the base evaluator; those funcs stay on the
+// *Macro that NewTimeMacro returned.
+func NewTimeMacro(indent, alias, layout string) *Macro {
+	return buildTimeMacro(indent, alias, layout)
+}
+
+var (
+	// Date type
+	// a date-time parsed via time.Parse using time.RFC3339. Routes
+	// needing a different layout should use a macro type built with
+	// NewTimeMacro instead of reconfiguring this one.
+	Date = buildTimeMacro("date", "", time.RFC3339)
+
+	// Time type
+	// same parsing rules as Date, kept as a distinct type so it can
+	// be registered under its own indent.
+	Time = buildTimeMacro("time", "", time.RFC3339)
+
+	// DateTime type
+	// same parsing rules as Date and Time, kept as a distinct type
+	// for the same reason.
+	DateTime = buildTimeMacro("datetime", "", time.RFC3339)
+
+	// Duration type
+	// a Go duration string, e.g. "1h30m", parsed via time.ParseDuration.
+	Duration = NewMacro("duration", "", false, false, func(paramValue string) bool {
+		_, err := time.ParseDuration(paramValue)
+		return err == nil
+	}).
+		RegisterFunc("min", func(min string) EvaluatorFunc {
+			minDur, err := time.ParseDuration(min)
+			return func(paramValue string) bool {
+				if err != nil {
+					return false
+				}
+				d, derr := time.ParseDuration(paramValue)
+				return derr == nil && d >= minDur
+			}
+		}).
+		RegisterFunc("max", func(max string) EvaluatorFunc {
+			maxDur, err := time.ParseDuration(max)
+			return func(paramValue string) bool {
+				if err != nil {
+					return false
+				}
+				d, derr := time.ParseDuration(paramValue)
+				return derr == nil && d <= maxDur
+			}
+		}).
+		RegisterFunc("range", func(min, max string) EvaluatorFunc {
+			minDur, minErr := time.ParseDuration(min)
+			maxDur, maxErr := time.ParseDuration(max)
+			return func(paramValue string) bool {
+				if minErr != nil || maxErr != nil {
+					return false
+				}
+				d, derr := time.ParseDuration(paramValue)
+				return derr == nil && d >= minDur && d <= maxDur
+			}
+		})
+
+	// ISO8601Duration type
+	// an ISO 8601 duration, e.g. "P3Y6M4DT12H30M5S". Years and months
+	// are approximated (365.25 and 30 days respectively) since they
+	// aren't fixed-length, so fractional years/months are rejected
+	// rather than silently rounded.
+	ISO8601Duration = NewMacro("iso8601_duration", "", false, false, func(paramValue string) bool {
+		_, ok := parseISO8601Duration(paramValue)
+		return ok
+	}).
+		RegisterFunc("min", func(min string) EvaluatorFunc {
+			minDur, ok := parseISO8601Duration(min)
+			return func(paramValue string) bool {
+				if !ok {
+					return false
+				}
+				d, dok := parseISO8601Duration(paramValue)
+				return dok && d >= minDur
+			}
+		}).
+		RegisterFunc("max", func(max string) EvaluatorFunc {
+			maxDur, ok := parseISO8601Duration(max)
+			return func(paramValue string) bool {
+				if !ok {
+					return false
+				}
+				d, dok := parseISO8601Duration(paramValue)
+				return dok && d <= maxDur
+			}
+		}).
+		RegisterFunc("range", func(min, max string) EvaluatorFunc {
+			minDur, minOk := parseISO8601Duration(min)
+			maxDur, maxOk := parseISO8601Duration(max)
+			return func(paramValue string) bool {
+				if !minOk || !maxOk {
+					return false
+				}
+				d, dok := parseISO8601Duration(paramValue)
+				return dok && d >= minDur && d <= maxDur
+			}
+		})
+)
+
+const (
+	isoDay   = 24 * time.Hour
+	isoYear  = time.Duration(365.25 * float64(isoDay))
+	isoMonth = 30 * isoDay
+)
+
+// parseISO8601Duration parses the "PnYnMnDTnHnMnS" form, splitting on
+// 'P'/'T' and scanning digit runs followed by a unit letter, summing
+// each into a time.Duration. Fractional years/months/days are rejected
+// since those units aren't fixed-length; fractional hours/minutes/
+// seconds are allowed.
+func parseISO8601Duration(s string) (time.Duration, bool) {
+	if len(s) == 0 || s[0] != 'P' {
+		return 0, false
+	}
+	s = s[1:]
+
+	datePart, timePart := s, ""
+	if idx := strings.IndexByte(s, 'T'); idx >= 0 {
+		datePart, timePart = s[:idx], s[idx+1:]
+	}
+
+	var total time.Duration
+
+	num := ""
+	for i := 0; i < len(datePart); i++ {
+		c := datePart[i]
+		switch {
+		case c >= '0' && c <= '9':
+			num += string(c)
+		case c == 'Y' || c == 'M' || c == 'D':
+			if num == "" {
+				return 0, false
+			}
+			n, err := strconv.ParseInt(num, 10, 64)
+			if err != nil {
+				return 0, false
+			}
+			switch c {
+			case 'Y':
+				total += time.Duration(n) * isoYear
+			case 'M':
+				total += time.Duration(n) * isoMonth
+			case 'D':
+				total += time.Duration(n) * isoDay
+			}
+			num = ""
+		default:
+			// includes '.', fractional date components aren't supported.
+			return 0, false
+		}
+	}
+	if num != "" {
+		return 0, false
+	}
+
+	for i := 0; i < len(timePart); i++ {
+		c := timePart[i]
+		switch {
+		case (c >= '0' && c <= '9') || c == '.':
+			num += string(c)
+		case c == 'H' || c == 'M' || c == 'S':
+			if num == "" {
+				return 0, false
+			}
+			f, err := strconv.ParseFloat(num, 64)
+			if err != nil {
+				return 0, false
+			}
+			switch c {
+			case 'H':
+				total += time.Duration(f * float64(time.Hour))
+			case 'M':
+				total += time.Duration(f * float64(time.Minute))
+			case 'S':
+				total += time.Duration(f * float64(time.Second))
+			}
+			num = ""
+		default:
+			return 0, false
+		}
+	}
+	if num != "" {
+		return 0, false
+	}
+
+	return total, true
+}
+
+// ParseRFC3339, ParseDuration and ParseISO8601Duration parse a param
+// value the same way Date/Time/DateTime, Duration and ISO8601Duration's
+// evaluators do. They back the ParamBinderFuncs registered below, and
+// are exported so callers that already have a raw param value (outside
+// a binder) can get the same parse without going through the registry.
+func ParseRFC3339(paramValue string) (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, paramValue)
+	return t, err == nil
+}
+
+func ParseDuration(paramValue string) (time.Duration, bool) {
+	d, err := time.ParseDuration(paramValue)
+	return d, err == nil
+}
+
+func ParseISO8601Duration(paramValue string) (time.Duration, bool) {
+	return parseISO8601Duration(paramValue)
+}
+
+func init() {
+	timeBinder := func(paramValue string) (interface{}, bool) {
+		return ParseRFC3339(paramValue)
+	}
+	RegisterParamBinder("date", timeBinder)
+	RegisterParamBinder("time", timeBinder)
+	RegisterParamBinder("datetime", timeBinder)
+
+	RegisterParamBinder("duration", func(paramValue string) (interface{}, bool) {
+		return ParseDuration(paramValue)
+	})
+	RegisterParamBinder("iso8601_duration", func(paramValue string) (interface{}, bool) {
+		return ParseISO8601Duration(paramValue)
+	})
+}
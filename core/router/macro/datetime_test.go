@@ -0,0 +1,94 @@
+package macro
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseISO8601Duration(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   time.Duration
+		wantOk bool
+	}{
+		{"P3Y6M4DT12H30M5S", 3*isoYear + 6*isoMonth + 4*isoDay + 12*time.Hour + 30*time.Minute + 5*time.Second, true},
+		{"PT1H", time.Hour, true},
+		{"P1D", isoDay, true},
+		{"PT0.5S", 500 * time.Millisecond, true},
+		{"P", 0, true},
+		{"", 0, false},
+		{"1D", 0, false},
+		{"PT", 0, true},
+		{"P1.5Y", 0, false},
+		{"P1Y2", 0, false},
+		{"PT1X", 0, false},
+		{"P1M1Y", isoMonth + isoYear, true},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseISO8601Duration(tt.in)
+		if ok != tt.wantOk {
+			t.Errorf("parseISO8601Duration(%q) ok = %v, want %v", tt.in, ok, tt.wantOk)
+			continue
+		}
+		if ok && got != tt.want {
+			t.Errorf("parseISO8601Duration(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBuildTimeMacroIndependentLayouts(t *testing.T) {
+	euro := NewTimeMacro("date_eu_test", "", "02-01-2006")
+
+	if ok := euro.Evaluator("31-01-2024"); !ok {
+		t.Fatalf("expected euro layout macro to accept 31-01-2024")
+	}
+	if ok := euro.Evaluator("2024-01-31T00:00:00Z"); ok {
+		t.Fatalf("expected euro layout macro to reject an RFC3339 value")
+	}
+
+	// Date must still default to RFC3339, unaffected by euro's layout.
+	if ok := Date.Evaluator("2024-01-31T00:00:00Z"); !ok {
+		t.Fatalf("expected Date to still accept RFC3339 after building a differently-layout-ed macro")
+	}
+	if ok := Date.Evaluator("31-01-2024"); ok {
+		t.Fatalf("expected Date to reject a non-RFC3339 value")
+	}
+}
+
+func TestDateTimeParamBinders(t *testing.T) {
+	const rfc3339Value = "2024-01-31T00:00:00Z"
+
+	for _, indent := range []string{"date", "time", "datetime"} {
+		binder, ok := ParamBinderFor(indent)
+		if !ok {
+			t.Fatalf("expected a ParamBinderFunc registered under %q", indent)
+		}
+		v, ok := binder(rfc3339Value)
+		if !ok {
+			t.Fatalf("%s binder(%q) failed to parse", indent, rfc3339Value)
+		}
+		want, _ := ParseRFC3339(rfc3339Value)
+		if v.(time.Time) != want {
+			t.Fatalf("%s binder(%q) = %v, want %v", indent, rfc3339Value, v, want)
+		}
+	}
+
+	durationBinder, ok := ParamBinderFor("duration")
+	if !ok {
+		t.Fatalf(`expected a ParamBinderFunc registered under "duration"`)
+	}
+	v, ok := durationBinder("1h30m")
+	if !ok || v.(time.Duration) != 90*time.Minute {
+		t.Fatalf(`duration binder("1h30m") = %v, %v, want 1h30m, true`, v, ok)
+	}
+
+	isoBinder, ok := ParamBinderFor("iso8601_duration")
+	if !ok {
+		t.Fatalf(`expected a ParamBinderFunc registered under "iso8601_duration"`)
+	}
+	v, ok = isoBinder("PT1H")
+	if !ok || v.(time.Duration) != time.Hour {
+		t.Fatalf(`iso8601_duration binder("PT1H") = %v, %v, want 1h, true`, v, ok)
+	}
+}
@@ -0,0 +1,93 @@
+package macro
+
+import "sync"
+
+// decodedCacheCap bounds how many decoded values are kept in memory. It
+// exists to amortize the decode cost of whatever's embedded in a
+// handful of hot routes' path segments, not to act as a general-purpose
+// store, so it stays small and evicts in FIFO order rather than
+// tracking real recency.
+const decodedCacheCap = 4096
+
+type decodedCache struct {
+	mu    sync.Mutex
+	order []string
+	data  map[string][]byte
+}
+
+func newDecodedCache() *decodedCache {
+	return &decodedCache{data: make(map[string][]byte)}
+}
+
+func (c *decodedCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.data[key]
+	return v, ok
+}
+
+func (c *decodedCache) put(key string, value []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.data[key]; exists {
+		return
+	}
+
+	if len(c.order) >= decodedCacheCap {
+		oldest := c.order[0]
+		c.order = c.order[1:]
+		delete(c.data, oldest)
+	}
+
+	c.order = append(c.order, key)
+	c.data[key] = value
+}
+
+var globalDecodedCache = newDecodedCache()
+
+// decodeCached returns the bytes paramValue decodes to under the named
+// opaque macro type ("json", "base64", "base64url" or "hex"), the same
+// decoding its evaluator performs to validate a route. It's backed by a
+// small process-wide cache keyed on (macroIndent, paramValue): since
+// decoding is a pure function of its input, a hit is always correct,
+// and a miss (first call, or evicted) just falls back to decoding again.
+//
+// This is a package-private memoization helper, not the request-scoped
+// ctx.Params().GetDecoded(name) the request described -- that needs a
+// cache keyed per-request on core/context.Context, which isn't part of
+// this package (or of this checkout), so it was never built. Don't read
+// this as a stand-in for that API; it only exists so the macros below
+// don't decode the same paramValue twice while validating one route.
+func decodeCached(macroIndent, paramValue string) ([]byte, bool) {
+	key := macroIndent + "\x00" + paramValue
+	if decoded, ok := globalDecodedCache.get(key); ok {
+		return decoded, true
+	}
+
+	var (
+		decoded []byte
+		ok      bool
+	)
+	switch macroIndent {
+	case "json":
+		decoded, ok = DecodeJSON(paramValue)
+	case "base64", "base64url":
+		// Accept any of the four common variants here: "charset"
+		// (registerBase64Funcs) is the only func that should ever
+		// narrow a route to one specific alphabet, so the shared
+		// decode step must stay permissive or "charset" would be
+		// ANDed against a base check that already rejected values
+		// charset alone would have accepted.
+		decoded, ok = DecodeBase64(allBase64Encodings, paramValue)
+	case "hex":
+		decoded, ok = DecodeHex(paramValue)
+	default:
+		return nil, false
+	}
+
+	if ok {
+		globalDecodedCache.put(key, decoded)
+	}
+	return decoded, ok
+}
@@ -0,0 +1,101 @@
+package macro
+
+import "testing"
+
+func TestDecodeCachedJSON(t *testing.T) {
+	decoded, ok := decodeCached("json", `{"a":1}`)
+	if !ok {
+		t.Fatalf("expected valid JSON to decode")
+	}
+	if string(decoded) != `{"a":1}` {
+		t.Fatalf("expected decoded bytes to match input, got %q", decoded)
+	}
+
+	if _, ok := decodeCached("json", "not json"); ok {
+		t.Fatalf("expected invalid JSON to fail")
+	}
+}
+
+func TestDecodeCachedBase64(t *testing.T) {
+	// "hi" base64-encoded, standard alphabet.
+	decoded, ok := decodeCached("base64", "aGk=")
+	if !ok || string(decoded) != "hi" {
+		t.Fatalf("expected \"hi\", got %q, ok=%v", decoded, ok)
+	}
+
+	if _, ok := decodeCached("base64", "not valid base64!!"); ok {
+		t.Fatalf("expected invalid base64 to fail")
+	}
+}
+
+func TestDecodeCachedBase64AcceptsEitherAlphabet(t *testing.T) {
+	// URL-safe-only value (standard alphabet would encode it with "+"/"/").
+	if _, ok := decodeCached("base64", "-_-__g=="); !ok {
+		t.Fatalf("expected the \"base64\" indent to accept a URL-safe-only value")
+	}
+	if _, ok := decodeCached("base64url", "-_-__g=="); !ok {
+		t.Fatalf("expected the \"base64url\" indent to accept a URL-safe-only value")
+	}
+}
+
+func TestDecodeCachedHex(t *testing.T) {
+	decoded, ok := decodeCached("hex", "68656c6c6f")
+	if !ok || string(decoded) != "hello" {
+		t.Fatalf("expected \"hello\", got %q, ok=%v", decoded, ok)
+	}
+
+	if _, ok := decodeCached("hex", "zz"); ok {
+		t.Fatalf("expected invalid hex to fail")
+	}
+}
+
+func TestDecodeCachedUnknownMacro(t *testing.T) {
+	if _, ok := decodeCached("nope", "anything"); ok {
+		t.Fatalf("expected an unknown macro indent to fail")
+	}
+}
+
+func TestDecodeCachedCachesResult(t *testing.T) {
+	const paramValue = "68656c6c6f"
+
+	first, ok := decodeCached("hex", paramValue)
+	if !ok {
+		t.Fatalf("expected first decode to succeed")
+	}
+
+	key := "hex" + "\x00" + paramValue
+	cached, ok := globalDecodedCache.get(key)
+	if !ok {
+		t.Fatalf("expected the decoded value to be cached under %q", key)
+	}
+	if string(cached) != string(first) {
+		t.Fatalf("cached value %q doesn't match decoded value %q", cached, first)
+	}
+}
+
+func TestDecodedCacheStaysBounded(t *testing.T) {
+	c := newDecodedCache()
+	for i := 0; i < decodedCacheCap+1; i++ {
+		c.put(string(rune('a'+i%26))+string(rune(i)), []byte{byte(i)})
+	}
+	if len(c.data) != decodedCacheCap {
+		t.Fatalf("expected cache to stay bounded at %d entries, got %d", decodedCacheCap, len(c.data))
+	}
+}
+
+func TestBase64CharsetNarrowsAfterPermissiveBase(t *testing.T) {
+	const urlOnly = "-_-__g=="
+
+	if !Base64.Evaluator(urlOnly) {
+		t.Fatalf("expected Base64's base evaluator to accept a URL-safe-only value")
+	}
+
+	// mustBase64Encoding + DecodeString is exactly what the "charset"
+	// func registered by registerBase64Funcs does with its argument.
+	if _, err := mustBase64Encoding("url").DecodeString(urlOnly); err != nil {
+		t.Fatalf(`expected charset("url")'s check to accept a URL-safe-only value`)
+	}
+	if _, err := mustBase64Encoding("standard").DecodeString(urlOnly); err == nil {
+		t.Fatalf(`expected charset("standard")'s check to reject a URL-safe-only value`)
+	}
+}
@@ -0,0 +1,189 @@
+package macro
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// Every evaluator below except "charset" goes through decodeCached
+// (decodedcache.go) so a
+// successful match is cached under (macro indent, raw value) instead of
+// being decoded again the next time something asks for it -- see that
+// file's doc comment for what's cached and what still isn't.
+
+// Base64Encodings and Base64URLEncodings are, in preference order, the
+// encodings DecodeBase64 tries when a caller wants to pick one family
+// explicitly. Base64 and Base64URL's own base evaluators don't use
+// these directly -- see allBase64Encodings below for why -- and neither
+// does "charset", which decodes with its one resolved *base64.Encoding.
+var (
+	Base64Encodings    = []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding}
+	Base64URLEncodings = []*base64.Encoding{base64.URLEncoding, base64.RawURLEncoding}
+
+	// allBase64Encodings is every variant Base64/Base64URL's base
+	// evaluator and "max_bytes" accept: a param is "valid base64" if
+	// any common alphabet round-trips it. Narrowing to one alphabet is
+	// "charset"'s job (registerBase64Funcs), so the shared base check
+	// must not itself be narrower than any charset a route might pick
+	// -- otherwise charset("url") ANDed against a standard-only base
+	// check would reject every genuinely URL-safe value.
+	allBase64Encodings = []*base64.Encoding{
+		base64.StdEncoding, base64.RawStdEncoding,
+		base64.URLEncoding, base64.RawURLEncoding,
+	}
+)
+
+func base64EncodingByName(charset string) (*base64.Encoding, bool) {
+	switch charset {
+	case "standard":
+		return base64.StdEncoding, true
+	case "url":
+		return base64.URLEncoding, true
+	case "raw":
+		return base64.RawStdEncoding, true
+	default:
+		return nil, false
+	}
+}
+
+// mustBase64Encoding panics for an unknown charset name, the same way
+// MustNewEvaluatorFromRegexp panics for a bad pattern: fail loudly at
+// route registration instead of letting every request silently mismatch.
+func mustBase64Encoding(charset string) *base64.Encoding {
+	enc, ok := base64EncodingByName(charset)
+	if !ok {
+		panic(`macro: base64: unknown charset "` + charset + `", want "standard", "url" or "raw"`)
+	}
+	return enc
+}
+
+func decodeBase64Any(encodings []*base64.Encoding, paramValue string) ([]byte, bool) {
+	for _, enc := range encodings {
+		if decoded, err := enc.DecodeString(paramValue); err == nil {
+			return decoded, true
+		}
+	}
+	return nil, false
+}
+
+// registerBase64Funcs adds the "max_bytes" and "charset" evaluator
+// funcs shared by Base64 and Base64URL. m.Indent() ("base64" or
+// "base64url") selects which of decodeCached's cases max_bytes decodes
+// with -- both resolve to allBase64Encodings, so max_bytes agrees with
+// whatever the base evaluator and "charset" accepted. "charset" pins
+// the param to exactly one named encoding; since the base evaluator
+// already accepts every alphabet (allBase64Encodings), ANDing it with
+// "charset" narrows the match down to that one alphabet instead of
+// vetoing it.
+func registerBase64Funcs(m *Macro) *Macro {
+	indent := m.Indent()
+
+	return m.
+		// checks if the decoded length doesn't exceed 'n' bytes.
+		RegisterFunc("max_bytes", func(n int) EvaluatorFunc {
+			return func(paramValue string) bool {
+				decoded, ok := decodeCached(indent, paramValue)
+				return ok && len(decoded) <= n
+			}
+		}).
+		// restricts the param to one base64 alphabet: "standard",
+		// "url" or "raw" (unpadded standard).
+		RegisterFunc("charset", func(charset string) EvaluatorFunc {
+			enc := mustBase64Encoding(charset)
+			return func(paramValue string) bool {
+				_, err := enc.DecodeString(paramValue)
+				return err == nil
+			}
+		})
+}
+
+// schemas is the user-registered JSON schema registry consulted by the
+// JSON macro's "schema" func.
+var schemas = map[string]func(decoded []byte) bool{}
+
+// RegisterSchema registers validate under name, for use with the JSON
+// macro's schema(name) evaluator func, e.g. {body:json:schema("order")}.
+func RegisterSchema(name string, validate func(decoded []byte) bool) {
+	schemas[name] = validate
+}
+
+var (
+	// JSON type
+	// validates that the param value decodes as JSON.
+	JSON = NewMacro("json", "", false, false, func(paramValue string) bool {
+		_, ok := decodeCached("json", paramValue)
+		return ok
+	}).
+		// checks if the value's length doesn't exceed 'n' bytes.
+		RegisterFunc("max_bytes", func(n int) EvaluatorFunc {
+			return func(paramValue string) bool {
+				decoded, ok := decodeCached("json", paramValue)
+				return ok && len(decoded) <= n
+			}
+		}).
+		// checks the value against a schema previously registered
+		// with RegisterSchema.
+		RegisterFunc("schema", func(name string) EvaluatorFunc {
+			return func(paramValue string) bool {
+				validate, ok := schemas[name]
+				if !ok {
+					return false
+				}
+				decoded, ok := decodeCached("json", paramValue)
+				return ok && validate(decoded)
+			}
+		})
+
+	// Base64 type
+	// validates that the param value decodes under any common base64
+	// alphabet (standard, url, padded or not); chain "charset" to
+	// require exactly one of them.
+	Base64 = registerBase64Funcs(NewMacro("base64", "", false, false, func(paramValue string) bool {
+		_, ok := decodeCached("base64", paramValue)
+		return ok
+	}))
+
+	// Base64URL type
+	// same as Base64, kept as a distinct type so it can be registered
+	// under its own indent; "charset" still picks the alphabet.
+	Base64URL = registerBase64Funcs(NewMacro("base64url", "", false, false, func(paramValue string) bool {
+		_, ok := decodeCached("base64url", paramValue)
+		return ok
+	}))
+
+	// Hex type
+	// validates that the param value decodes as hexadecimal.
+	Hex = NewMacro("hex", "", false, false, func(paramValue string) bool {
+		_, ok := decodeCached("hex", paramValue)
+		return ok
+	}).
+		// checks if the decoded length doesn't exceed 'n' bytes.
+		RegisterFunc("max_bytes", func(n int) EvaluatorFunc {
+			return func(paramValue string) bool {
+				decoded, ok := decodeCached("hex", paramValue)
+				return ok && len(decoded) <= n
+			}
+		})
+)
+
+// DecodeBase64 decodes paramValue trying each of encodings in order,
+// e.g. Base64Encodings or Base64URLEncodings, or a single-element slice
+// built from mustBase64Encoding's result.
+func DecodeBase64(encodings []*base64.Encoding, paramValue string) ([]byte, bool) {
+	return decodeBase64Any(encodings, paramValue)
+}
+
+// DecodeHex decodes paramValue as hexadecimal.
+func DecodeHex(paramValue string) ([]byte, bool) {
+	decoded, err := hex.DecodeString(paramValue)
+	return decoded, err == nil
+}
+
+// DecodeJSON validates paramValue as JSON and returns it as raw bytes.
+func DecodeJSON(paramValue string) ([]byte, bool) {
+	if !json.Valid([]byte(paramValue)) {
+		return nil, false
+	}
+	return []byte(paramValue), true
+}
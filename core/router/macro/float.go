@@ -0,0 +1,255 @@
+package macro
+
+import "strconv"
+
+var (
+	// Float64 or decimal type
+	// any IEEE-754 double-precision number, e.g. "19.99" or "-3.5".
+	Float64 = NewMacro("float64", "decimal", false, false, func(paramValue string) bool {
+		_, err := strconv.ParseFloat(paramValue, 64)
+		return err == nil
+	}).
+		// checks if the param value's float64 representation is
+		// bigger or equal than 'min'.
+		RegisterFunc("min", func(min float64) EvaluatorFunc {
+			return func(paramValue string) bool {
+				n, err := strconv.ParseFloat(paramValue, 64)
+				if err != nil {
+					return false
+				}
+				return n >= min
+			}
+		}).
+		// checks if the param value's float64 representation is
+		// smaller or equal than 'max'.
+		RegisterFunc("max", func(max float64) EvaluatorFunc {
+			return func(paramValue string) bool {
+				n, err := strconv.ParseFloat(paramValue, 64)
+				if err != nil {
+					return false
+				}
+				return n <= max
+			}
+		}).
+		// checks if the param value's float64 representation is
+		// between min and max, including 'min' and 'max'.
+		RegisterFunc("range", func(min, max float64) EvaluatorFunc {
+			return func(paramValue string) bool {
+				n, err := strconv.ParseFloat(paramValue, 64)
+				if err != nil {
+					return false
+				}
+
+				if n < min || n > max {
+					return false
+				}
+				return true
+			}
+		}).
+		// checks if the param value's float64 representation is
+		// strictly greater than 'n'.
+		RegisterFunc("gt", func(n float64) EvaluatorFunc {
+			return func(paramValue string) bool {
+				v, err := strconv.ParseFloat(paramValue, 64)
+				return err == nil && v > n
+			}
+		}).
+		// checks if the param value's float64 representation is
+		// greater than or equal to 'n'.
+		RegisterFunc("ge", func(n float64) EvaluatorFunc {
+			return func(paramValue string) bool {
+				v, err := strconv.ParseFloat(paramValue, 64)
+				return err == nil && v >= n
+			}
+		}).
+		// checks if the param value's float64 representation is
+		// strictly smaller than 'n'.
+		RegisterFunc("lt", func(n float64) EvaluatorFunc {
+			return func(paramValue string) bool {
+				v, err := strconv.ParseFloat(paramValue, 64)
+				return err == nil && v < n
+			}
+		}).
+		// checks if the param value's float64 representation is
+		// smaller than or equal to 'n'.
+		RegisterFunc("le", func(n float64) EvaluatorFunc {
+			return func(paramValue string) bool {
+				v, err := strconv.ParseFloat(paramValue, 64)
+				return err == nil && v <= n
+			}
+		}).
+		// checks if the param value has at most 'maxDecimals' digits
+		// after the decimal point. Rejects scientific notation
+		// ("1e10") outright -- "precision" and "scientific" check
+		// mutually exclusive formats, so don't chain both on one route.
+		RegisterFunc("precision", func(maxDecimals int) EvaluatorFunc {
+			return func(paramValue string) bool {
+				return hasPrecision(paramValue, maxDecimals)
+			}
+		}).
+		// checks if the param value is written in scientific
+		// notation, e.g. "1.5e-10".
+		RegisterFunc("scientific", func() EvaluatorFunc {
+			return isScientificNotation
+		})
+
+	// Float32 type
+	// any IEEE-754 single-precision number.
+	Float32 = NewMacro("float32", "", false, false, func(paramValue string) bool {
+		_, err := strconv.ParseFloat(paramValue, 32)
+		return err == nil
+	}).
+		// checks if the param value's float32 representation is
+		// bigger or equal than 'min'.
+		RegisterFunc("min", func(min float32) EvaluatorFunc {
+			return func(paramValue string) bool {
+				n, err := strconv.ParseFloat(paramValue, 32)
+				if err != nil {
+					return false
+				}
+				return float32(n) >= min
+			}
+		}).
+		// checks if the param value's float32 representation is
+		// smaller or equal than 'max'.
+		RegisterFunc("max", func(max float32) EvaluatorFunc {
+			return func(paramValue string) bool {
+				n, err := strconv.ParseFloat(paramValue, 32)
+				if err != nil {
+					return false
+				}
+				return float32(n) <= max
+			}
+		}).
+		// checks if the param value's float32 representation is
+		// between min and max, including 'min' and 'max'.
+		RegisterFunc("range", func(min, max float32) EvaluatorFunc {
+			return func(paramValue string) bool {
+				n, err := strconv.ParseFloat(paramValue, 32)
+				if err != nil {
+					return false
+				}
+
+				if v := float32(n); v < min || v > max {
+					return false
+				}
+				return true
+			}
+		}).
+		// checks if the param value's float32 representation is
+		// strictly greater than 'n'.
+		RegisterFunc("gt", func(n float32) EvaluatorFunc {
+			return func(paramValue string) bool {
+				v, err := strconv.ParseFloat(paramValue, 32)
+				return err == nil && float32(v) > n
+			}
+		}).
+		// checks if the param value's float32 representation is
+		// greater than or equal to 'n'.
+		RegisterFunc("ge", func(n float32) EvaluatorFunc {
+			return func(paramValue string) bool {
+				v, err := strconv.ParseFloat(paramValue, 32)
+				return err == nil && float32(v) >= n
+			}
+		}).
+		// checks if the param value's float32 representation is
+		// strictly smaller than 'n'.
+		RegisterFunc("lt", func(n float32) EvaluatorFunc {
+			return func(paramValue string) bool {
+				v, err := strconv.ParseFloat(paramValue, 32)
+				return err == nil && float32(v) < n
+			}
+		}).
+		// checks if the param value's float32 representation is
+		// smaller than or equal to 'n'.
+		RegisterFunc("le", func(n float32) EvaluatorFunc {
+			return func(paramValue string) bool {
+				v, err := strconv.ParseFloat(paramValue, 32)
+				return err == nil && float32(v) <= n
+			}
+		}).
+		// checks if the param value has at most 'maxDecimals' digits
+		// after the decimal point; see Float64's "precision" for the
+		// note on why it can't be chained with "scientific".
+		RegisterFunc("precision", func(maxDecimals int) EvaluatorFunc {
+			return func(paramValue string) bool {
+				return hasPrecision(paramValue, maxDecimals)
+			}
+		}).
+		// checks if the param value is written in scientific notation.
+		RegisterFunc("scientific", func() EvaluatorFunc {
+			return isScientificNotation
+		})
+)
+
+// hasPrecision reports whether paramValue, split on its decimal point
+// (no regex), has no more than maxDecimals digits after it. Values in
+// scientific notation are always rejected here -- "scientific" checks
+// a disjoint format, so it's an alternative to "precision" on a route,
+// never a modifier chained alongside it.
+func hasPrecision(paramValue string, maxDecimals int) bool {
+	if isScientificNotation(paramValue) {
+		return false
+	}
+
+	if _, err := strconv.ParseFloat(paramValue, 64); err != nil {
+		return false
+	}
+
+	dot := -1
+	for i := 0; i < len(paramValue); i++ {
+		if paramValue[i] == '.' {
+			dot = i
+			break
+		}
+	}
+
+	if dot == -1 {
+		return true
+	}
+
+	return len(paramValue)-dot-1 <= maxDecimals
+}
+
+// isScientificNotation reports whether paramValue is a valid float
+// written with an "e"/"E" exponent, e.g. "1.5e-10".
+func isScientificNotation(paramValue string) bool {
+	hasExponent := false
+	for i := 0; i < len(paramValue); i++ {
+		if c := paramValue[i]; c == 'e' || c == 'E' {
+			hasExponent = true
+			break
+		}
+	}
+
+	if !hasExponent {
+		return false
+	}
+
+	_, err := strconv.ParseFloat(paramValue, 64)
+	return err == nil
+}
+
+// ParseFloat64 and ParseFloat32 parse a param value the same way
+// Float64 and Float32's evaluators do. They back the "float64"/
+// "float32" ParamBinderFuncs registered below, and are exported so
+// callers that already have a raw param value (outside a binder) can
+// get the same parse without going through the registry.
+func ParseFloat64(paramValue string) (float64, bool) {
+	v, err := strconv.ParseFloat(paramValue, 64)
+	return v, err == nil
+}
+
+func ParseFloat32(paramValue string) (float32, bool) {
+	v, err := strconv.ParseFloat(paramValue, 32)
+	return float32(v), err == nil
+}
+
+func init() {
+	RegisterParamBinder("float64", func(paramValue string) (interface{}, bool) {
+		return ParseFloat64(paramValue)
+	})
+	RegisterParamBinder("float32", func(paramValue string) (interface{}, bool) {
+		return ParseFloat32(paramValue)
+	})
+}
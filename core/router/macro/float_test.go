@@ -0,0 +1,82 @@
+package macro
+
+import "testing"
+
+func TestHasPrecision(t *testing.T) {
+	tests := []struct {
+		in          string
+		maxDecimals int
+		want        bool
+	}{
+		{"19.99", 2, true},
+		{"19.999", 2, false},
+		{"19", 2, true},
+		{"-3.5", 1, true},
+		{"-3.55", 1, false},
+		{"1e10", 2, false}, // scientific notation is always rejected
+		{"not a float", 2, false},
+	}
+
+	for _, tt := range tests {
+		if got := hasPrecision(tt.in, tt.maxDecimals); got != tt.want {
+			t.Errorf("hasPrecision(%q, %d) = %v, want %v", tt.in, tt.maxDecimals, got, tt.want)
+		}
+	}
+}
+
+func TestIsScientificNotation(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"1.5e-10", true},
+		{"1E10", true},
+		{"19.99", false},
+		{"not a float", false},
+		{"e10", false},
+	}
+
+	for _, tt := range tests {
+		if got := isScientificNotation(tt.in); got != tt.want {
+			t.Errorf("isScientificNotation(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseFloat64AndFloat32(t *testing.T) {
+	v64, ok := ParseFloat64("19.99")
+	if !ok || v64 != 19.99 {
+		t.Fatalf("ParseFloat64(%q) = %v, %v, want 19.99, true", "19.99", v64, ok)
+	}
+	if _, ok := ParseFloat64("not a float"); ok {
+		t.Fatalf("expected ParseFloat64 to reject a non-float value")
+	}
+
+	v32, ok := ParseFloat32("3.5")
+	if !ok || v32 != 3.5 {
+		t.Fatalf("ParseFloat32(%q) = %v, %v, want 3.5, true", "3.5", v32, ok)
+	}
+	if _, ok := ParseFloat32("not a float"); ok {
+		t.Fatalf("expected ParseFloat32 to reject a non-float value")
+	}
+}
+
+func TestFloatParamBinders(t *testing.T) {
+	binder64, ok := ParamBinderFor("float64")
+	if !ok {
+		t.Fatalf(`expected a ParamBinderFunc registered under "float64"`)
+	}
+	v, ok := binder64("19.99")
+	if !ok || v.(float64) != 19.99 {
+		t.Fatalf(`float64 binder("19.99") = %v, %v, want 19.99, true`, v, ok)
+	}
+
+	binder32, ok := ParamBinderFor("float32")
+	if !ok {
+		t.Fatalf(`expected a ParamBinderFunc registered under "float32"`)
+	}
+	v, ok = binder32("3.5")
+	if !ok || v.(float32) != 3.5 {
+		t.Fatalf(`float32 binder("3.5") = %v, %v, want 3.5, true`, v, ok)
+	}
+}
@@ -0,0 +1,133 @@
+package macro
+
+// crockfordBase32 is the alphabet used by ULID, a variant of Base32
+// (Crockford's) that excludes the letters I, L, O and U so that decoded
+// values can't be confused with digits or with each other.
+const crockfordBase32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// crockfordValue maps an ASCII byte to its Crockford base32 value, or -1
+// if the byte is not part of the alphabet.
+var crockfordValue [256]int8
+
+func init() {
+	for i := range crockfordValue {
+		crockfordValue[i] = -1
+	}
+	for i := 0; i < len(crockfordBase32); i++ {
+		crockfordValue[crockfordBase32[i]] = int8(i)
+	}
+}
+
+var (
+	// UUID type
+	// validates a hyphenated RFC 4122 UUID,
+	// e.g. "550e8400-e29b-41d4-a716-446655440000".
+	UUID = NewMacro("uuid", "", false, false, MustNewEvaluatorFromRegexp("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")).
+		// checks if the uuid's version nibble (the first hex digit
+		// of the third group) matches the given version.
+		RegisterFunc("version", func(version int) EvaluatorFunc {
+			return func(paramValue string) bool {
+				if len(paramValue) != 36 {
+					return false
+				}
+				n, ok := hexNibble(paramValue[14])
+				return ok && int(n) == version
+			}
+		}).
+		// checks if the uuid's variant nibble (the first hex digit of
+		// the fourth group) matches one of the given variants, e.g.
+		// variant("8", "9", "a", "b") for the RFC 4122 variant.
+		RegisterFunc("variant", func(variants ...string) EvaluatorFunc {
+			return func(paramValue string) bool {
+				if len(paramValue) != 36 {
+					return false
+				}
+				c := paramValue[19]
+				for _, v := range variants {
+					if len(v) == 1 && lowerByte(c) == lowerByte(v[0]) {
+						return true
+					}
+				}
+				return false
+			}
+		})
+
+	// ULID type
+	// validates a 26-character Crockford base32 ULID,
+	// e.g. "01ARZ3NDEKTSV4RRFFQ69G5FAV".
+	ULID = NewMacro("ulid", "", false, false, isValidULID).
+		// checks if the ULID's embedded 48-bit millisecond timestamp
+		// is between 'min' and 'max', including 'min' and 'max'.
+		RegisterFunc("timestamp", func(min, max int64) EvaluatorFunc {
+			return func(paramValue string) bool {
+				ts, ok := ulidTimestamp(paramValue)
+				if !ok {
+					return false
+				}
+				return ts >= min && ts <= max
+			}
+		})
+)
+
+// hexNibble reports the value of a single hex digit.
+func hexNibble(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	default:
+		return 0, false
+	}
+}
+
+func lowerByte(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+	return c
+}
+
+// isValidULID reports whether paramValue is a well-formed, 26-character
+// Crockford base32 ULID. It doesn't range-check the timestamp part
+// beyond rejecting values that can't possibly be one (see below), use
+// the "timestamp" evaluator func for an actual min/max check.
+func isValidULID(paramValue string) bool {
+	if len(paramValue) != 26 {
+		return false
+	}
+
+	for i := 0; i < len(paramValue); i++ {
+		if crockfordValue[paramValue[i]] == -1 {
+			return false
+		}
+	}
+
+	// The 26 characters encode 130 bits (26*5), but a ULID only has
+	// 128: an 80-bit random part plus a 48-bit timestamp. The 2
+	// extra bits live at the top of the first character, so it must
+	// decode to 0-7 (0b00xxx) -- anything higher would overflow the
+	// 48-bit timestamp the "timestamp" func decodes below.
+	if crockfordValue[paramValue[0]] > 7 {
+		return false
+	}
+
+	return true
+}
+
+// ulidTimestamp decodes the leading 48-bit millisecond timestamp
+// out of the first 10 characters of a ULID.
+func ulidTimestamp(paramValue string) (int64, bool) {
+	if !isValidULID(paramValue) {
+		return 0, false
+	}
+
+	var ts int64
+	for i := 0; i < 10; i++ {
+		ts = ts<<5 | int64(crockfordValue[paramValue[i]])
+	}
+
+	return ts, true
+}
@@ -0,0 +1,49 @@
+package macro
+
+import "testing"
+
+func TestIsValidULID(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAV", true},
+		{"000000000000000000000000000", false}, // 27 chars, too long
+		{"00000000000000000000000000", true},   // 26 chars, all zero
+		{"7ZZZZZZZZZZZZZZZZZZZZZZZZZ", true},   // leading char at the boundary (7)
+		{"8ZZZZZZZZZZZZZZZZZZZZZZZZZ", false},  // leading char one past the boundary
+		{"ZZZZZZZZZZZZZZZZZZZZZZZZZZ", false},  // leading char way over
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAI", false},  // 'I' isn't in the Crockford alphabet
+		{"short", false},
+	}
+
+	for _, tt := range tests {
+		if got := isValidULID(tt.in); got != tt.want {
+			t.Errorf("isValidULID(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestULIDTimestampFitsIn48Bits(t *testing.T) {
+	ts, ok := ulidTimestamp("7ZZZZZZZZZZZZZZZZZZZZZZZZZ")
+	if !ok {
+		t.Fatalf("expected a ULID with leading char 7 to be a valid timestamp")
+	}
+	const maxUint48 = 1<<48 - 1
+	if ts != maxUint48 {
+		t.Fatalf("expected the maximal 48-bit timestamp %d, got %d", maxUint48, ts)
+	}
+
+	if _, ok := ulidTimestamp("8ZZZZZZZZZZZZZZZZZZZZZZZZZ"); ok {
+		t.Fatalf("expected a ULID with an out-of-range leading char to be rejected")
+	}
+}
+
+func TestUUIDEvaluator(t *testing.T) {
+	if !UUID.Evaluator("550e8400-e29b-41d4-a716-446655440000") {
+		t.Fatalf("expected a well-formed UUID to validate")
+	}
+	if UUID.Evaluator("not-a-uuid") {
+		t.Fatalf("expected a malformed UUID to be rejected")
+	}
+}
@@ -12,7 +12,7 @@ var (
 	// Allows anything (single path segment, as everything except the `Path`).
 	String = NewMacro("string", "", true, false, func(string) bool { return true }).
 		RegisterFunc("regexp", func(expr string) EvaluatorFunc {
-			return MustNewEvaluatorFromRegexp(expr)
+			return TrackEvaluator("string", "regexp", MustNewEvaluatorFromRegexp(expr))
 		}).
 		// checks if param value starts with the 'prefix' arg
 		RegisterFunc("prefix", func(prefix string) EvaluatorFunc {
@@ -236,7 +236,7 @@ var (
 
 	// Alphabetical letter type
 	// letters only (upper or lowercase)
-	Alphabetical = NewMacro("alphabetical", "", false, false, MustNewEvaluatorFromRegexp("^[a-zA-Z ]+$"))
+	Alphabetical = NewMacro("alphabetical", "", false, false, TrackEvaluator("alphabetical", "", MustNewEvaluatorFromRegexp("^[a-zA-Z ]+$")))
 	// File type
 	// letters (upper or lowercase)
 	// numbers (0-9)
@@ -244,7 +244,10 @@ var (
 	// dash (-)
 	// point (.)
 	// no spaces! or other character
-	File = NewMacro("file", "", false, false, MustNewEvaluatorFromRegexp("^[a-zA-Z0-9_.-]*$"))
+	//
+	// Regex-backed, so its evaluator is wrapped with TrackEvaluator:
+	// see stats.go for the EWMA latency tracking this enables.
+	File = NewMacro("file", "", false, false, TrackEvaluator("file", "", MustNewEvaluatorFromRegexp("^[a-zA-Z0-9_.-]*$")))
 	// Path type
 	// anything, should be the last part
 	//
@@ -260,8 +263,26 @@ var (
 		Int64,
 		Uint8,
 		Uint64,
+		Float32,
+		Float64,
 		Bool,
 		Alphabetical,
+		UUID,
+		ULID,
+		IP,
+		IPv4,
+		IPv6,
+		CIDR,
+		Hostname,
+		Date,
+		Time,
+		DateTime,
+		Duration,
+		ISO8601Duration,
+		JSON,
+		Base64,
+		Base64URL,
+		Hex,
 		Path,
 	}
 )
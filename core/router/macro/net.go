@@ -0,0 +1,195 @@
+package macro
+
+import "net/netip"
+
+var (
+	// IP type
+	// validates any IPv4 or IPv6 address, parsed through "net/netip"
+	// so the accepted forms match the standard library exactly
+	// (including zones, e.g. "fe80::1%eth0").
+	IP = NewMacro("ip", "", false, false, func(paramValue string) bool {
+		_, err := netip.ParseAddr(paramValue)
+		return err == nil
+	}).
+		RegisterFunc("in_cidr", inCIDRFunc(netip.ParseAddr)).
+		RegisterFunc("is_private", isPrivateFunc(netip.ParseAddr)).
+		RegisterFunc("is_loopback", isLoopbackFunc(netip.ParseAddr)).
+		RegisterFunc("is_global_unicast", isGlobalUnicastFunc(netip.ParseAddr))
+
+	// IPv4 type
+	// validates an IPv4 address only, e.g. "192.168.1.1".
+	IPv4 = NewMacro("ip4", "ipv4", false, false, func(paramValue string) bool {
+		addr, err := netip.ParseAddr(paramValue)
+		return err == nil && addr.Is4()
+	}).
+		RegisterFunc("in_cidr", inCIDRFunc(parseIP4)).
+		RegisterFunc("is_private", isPrivateFunc(parseIP4)).
+		RegisterFunc("is_loopback", isLoopbackFunc(parseIP4)).
+		RegisterFunc("is_global_unicast", isGlobalUnicastFunc(parseIP4))
+
+	// IPv6 type
+	// validates an IPv6 address only, e.g. "2001:db8::1".
+	IPv6 = NewMacro("ip6", "ipv6", false, false, func(paramValue string) bool {
+		addr, err := netip.ParseAddr(paramValue)
+		return err == nil && addr.Is6() && !addr.Is4In6()
+	}).
+		RegisterFunc("in_cidr", inCIDRFunc(parseIP6)).
+		RegisterFunc("is_private", isPrivateFunc(parseIP6)).
+		RegisterFunc("is_loopback", isLoopbackFunc(parseIP6)).
+		RegisterFunc("is_global_unicast", isGlobalUnicastFunc(parseIP6))
+
+	// CIDR type
+	// validates an IPv4 or IPv6 CIDR notation, e.g. "10.0.0.0/8".
+	CIDR = NewMacro("cidr", "", false, false, func(paramValue string) bool {
+		_, err := netip.ParsePrefix(paramValue)
+		return err == nil
+	}).
+		// checks if the CIDR contains the given address.
+		RegisterFunc("contains", func(address string) EvaluatorFunc {
+			addr, err := netip.ParseAddr(address)
+			return func(paramValue string) bool {
+				if err != nil {
+					return false
+				}
+				prefix, perr := netip.ParsePrefix(paramValue)
+				if perr != nil {
+					return false
+				}
+				return prefix.Contains(addr)
+			}
+		})
+
+	// Hostname type
+	// validates an RFC 1123 hostname: up to 253 characters in total,
+	// each label up to 63 characters, letters, digits and hyphens only,
+	// labels may not start or end with a hyphen.
+	Hostname = NewMacro("hostname", "", false, false, isValidHostname)
+)
+
+// parseIP4 parses an IPv4 address and fails for anything else,
+// including IPv4-in-IPv6 forms.
+func parseIP4(s string) (netip.Addr, error) {
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	if !addr.Is4() {
+		return netip.Addr{}, errNotIP4
+	}
+	return addr, nil
+}
+
+// parseIP6 parses an IPv6 address and fails for IPv4 and IPv4-in-IPv6 forms.
+func parseIP6(s string) (netip.Addr, error) {
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	if !addr.Is6() || addr.Is4In6() {
+		return netip.Addr{}, errNotIP6
+	}
+	return addr, nil
+}
+
+var (
+	errNotIP4 = netipError("not an IPv4 address")
+	errNotIP6 = netipError("not an IPv6 address")
+)
+
+// netipError is a trivial error type, avoiding an "errors" import for
+// two static messages.
+type netipError string
+
+func (e netipError) Error() string { return string(e) }
+
+func inCIDRFunc(parse func(string) (netip.Addr, error)) func(string) EvaluatorFunc {
+	return func(cidr string) EvaluatorFunc {
+		prefix, err := netip.ParsePrefix(cidr)
+		return func(paramValue string) bool {
+			if err != nil {
+				return false
+			}
+			addr, aerr := parse(paramValue)
+			if aerr != nil {
+				return false
+			}
+			return prefix.Contains(addr)
+		}
+	}
+}
+
+func isPrivateFunc(parse func(string) (netip.Addr, error)) func() EvaluatorFunc {
+	return func() EvaluatorFunc {
+		return func(paramValue string) bool {
+			addr, err := parse(paramValue)
+			return err == nil && addr.IsPrivate()
+		}
+	}
+}
+
+func isLoopbackFunc(parse func(string) (netip.Addr, error)) func() EvaluatorFunc {
+	return func() EvaluatorFunc {
+		return func(paramValue string) bool {
+			addr, err := parse(paramValue)
+			return err == nil && addr.IsLoopback()
+		}
+	}
+}
+
+func isGlobalUnicastFunc(parse func(string) (netip.Addr, error)) func() EvaluatorFunc {
+	return func() EvaluatorFunc {
+		return func(paramValue string) bool {
+			addr, err := parse(paramValue)
+			return err == nil && addr.IsGlobalUnicast()
+		}
+	}
+}
+
+// isValidHostname reports whether paramValue is a valid RFC 1123 hostname.
+func isValidHostname(paramValue string) bool {
+	if len(paramValue) == 0 || len(paramValue) > 253 {
+		return false
+	}
+
+	labels := splitHostnameLabels(paramValue)
+	for _, label := range labels {
+		if !isValidHostnameLabel(label) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func splitHostnameLabels(hostname string) []string {
+	var labels []string
+	start := 0
+	for i := 0; i < len(hostname); i++ {
+		if hostname[i] == '.' {
+			labels = append(labels, hostname[start:i])
+			start = i + 1
+		}
+	}
+	labels = append(labels, hostname[start:])
+	return labels
+}
+
+func isValidHostnameLabel(label string) bool {
+	if len(label) == 0 || len(label) > 63 {
+		return false
+	}
+
+	if label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		isAlnum := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !isAlnum && c != '-' {
+			return false
+		}
+	}
+
+	return true
+}
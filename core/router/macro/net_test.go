@@ -0,0 +1,175 @@
+package macro
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestIPEvaluator(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"192.168.1.1", true},
+		{"2001:db8::1", true},
+		{"fe80::1%eth0", true},
+		{"not an ip", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IP.Evaluator(tt.in); got != tt.want {
+			t.Errorf("IP.Evaluator(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIPv4AndIPv6Evaluators(t *testing.T) {
+	if !IPv4.Evaluator("192.168.1.1") {
+		t.Fatalf("expected IPv4 to accept an IPv4 address")
+	}
+	if IPv4.Evaluator("2001:db8::1") {
+		t.Fatalf("expected IPv4 to reject an IPv6 address")
+	}
+	if IPv4.Evaluator("::ffff:192.168.1.1") {
+		t.Fatalf("expected IPv4 to reject an IPv4-in-IPv6 address")
+	}
+
+	if !IPv6.Evaluator("2001:db8::1") {
+		t.Fatalf("expected IPv6 to accept an IPv6 address")
+	}
+	if IPv6.Evaluator("192.168.1.1") {
+		t.Fatalf("expected IPv6 to reject an IPv4 address")
+	}
+	if IPv6.Evaluator("::ffff:192.168.1.1") {
+		t.Fatalf("expected IPv6 to reject an IPv4-in-IPv6 address")
+	}
+}
+
+func TestCIDREvaluator(t *testing.T) {
+	if !CIDR.Evaluator("10.0.0.0/8") {
+		t.Fatalf("expected CIDR to accept a valid IPv4 prefix")
+	}
+	if !CIDR.Evaluator("2001:db8::/32") {
+		t.Fatalf("expected CIDR to accept a valid IPv6 prefix")
+	}
+	if CIDR.Evaluator("not a cidr") {
+		t.Fatalf("expected CIDR to reject a malformed value")
+	}
+}
+
+func TestInCIDRFunc(t *testing.T) {
+	inRange := inCIDRFunc(parseIP4)("10.0.0.0/8")
+	if !inRange("10.1.2.3") {
+		t.Fatalf("expected 10.1.2.3 to be contained in 10.0.0.0/8")
+	}
+	if inRange("192.168.1.1") {
+		t.Fatalf("expected 192.168.1.1 to not be contained in 10.0.0.0/8")
+	}
+	if inRange("not an ip") {
+		t.Fatalf("expected an unparseable address to fail the check")
+	}
+
+	badPrefix := inCIDRFunc(parseIP4)("not a cidr")
+	if badPrefix("10.1.2.3") {
+		t.Fatalf("expected a malformed CIDR argument to always reject")
+	}
+}
+
+func TestIsPrivateLoopbackGlobalUnicastFuncs(t *testing.T) {
+	isPrivate := isPrivateFunc(netip.ParseAddr)()
+	if !isPrivate("10.0.0.1") {
+		t.Fatalf("expected 10.0.0.1 to be private")
+	}
+	if isPrivate("8.8.8.8") {
+		t.Fatalf("expected 8.8.8.8 to not be private")
+	}
+
+	isLoopback := isLoopbackFunc(netip.ParseAddr)()
+	if !isLoopback("127.0.0.1") {
+		t.Fatalf("expected 127.0.0.1 to be a loopback address")
+	}
+	if isLoopback("8.8.8.8") {
+		t.Fatalf("expected 8.8.8.8 to not be a loopback address")
+	}
+
+	isGlobalUnicast := isGlobalUnicastFunc(netip.ParseAddr)()
+	if !isGlobalUnicast("8.8.8.8") {
+		t.Fatalf("expected 8.8.8.8 to be a global unicast address")
+	}
+	if isGlobalUnicast("127.0.0.1") {
+		t.Fatalf("expected 127.0.0.1 to not be a global unicast address")
+	}
+}
+
+func TestHostnameEvaluator(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"example.com", true},
+		{"sub.example.com", true},
+		{"a", true},
+		{"-bad.example.com", false},
+		{"bad-.example.com", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := Hostname.Evaluator(tt.in); got != tt.want {
+			t.Errorf("Hostname.Evaluator(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSplitHostnameLabels(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"example.com", []string{"example", "com"}},
+		{"a.b.c", []string{"a", "b", "c"}},
+		{"single", []string{"single"}},
+	}
+
+	for _, tt := range tests {
+		got := splitHostnameLabels(tt.in)
+		if len(got) != len(tt.want) {
+			t.Fatalf("splitHostnameLabels(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Fatalf("splitHostnameLabels(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestIsValidHostnameLabel(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"example", true},
+		{"ex-ample", true},
+		{"-example", false},
+		{"example-", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := isValidHostnameLabel(tt.in); got != tt.want {
+			t.Errorf("isValidHostnameLabel(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIsValidHostnameTooLong(t *testing.T) {
+	long := make([]byte, 254)
+	for i := range long {
+		long[i] = 'a'
+	}
+	if isValidHostname(string(long)) {
+		t.Fatalf("expected a 254-character hostname to be rejected")
+	}
+}
@@ -0,0 +1,207 @@
+package macro
+
+import (
+	"sync"
+	"time"
+)
+
+// StatsEnabled toggles per-evaluator latency tracking package-wide.
+// It defaults to false so the instrumentation is a true no-op on
+// deployments that don't need the visibility; while this is false, the
+// wrapper TrackEvaluator returns just calls the wrapped evaluator and
+// records nothing.
+var StatsEnabled = false
+
+// warmupSamples is the number of calls averaged plainly before the
+// EWMA takes over, so a cold first call (lazy regexp compilation,
+// page faults, ...) doesn't permanently skew the running average.
+const warmupSamples = 5
+
+// defaultEWMADecay mirrors the ~0.1 decay commonly used by RTT
+// trackers: newAvg = decay*sample + (1-decay)*oldAvg.
+const defaultEWMADecay = 0.1
+
+// ewmaDecay is the decay factor new funcStats are created with; change
+// it with SetEWMADecay. Guarded by statsMu, the same as stats itself.
+var ewmaDecay = defaultEWMADecay
+
+// SetEWMADecay overrides the decay factor used by every evaluator's
+// EWMA latency from now on. It only affects funcStats created after
+// the call -- evaluators already being tracked keep the decay they
+// started with, the same way toggling StatsEnabled doesn't rewrite
+// samples collected before the toggle. Pass 0 to restore the default.
+func SetEWMADecay(decay float64) {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	if decay == 0 {
+		decay = defaultEWMADecay
+	}
+	ewmaDecay = decay
+}
+
+// MacroFuncStat is a point-in-time snapshot of one evaluator's call
+// statistics, returned by (*Macro).Stats and Macros.SlowerThan.
+type MacroFuncStat struct {
+	Macro      string
+	Func       string
+	Calls      uint64
+	Rejections uint64
+	EWMA       time.Duration
+}
+
+// RejectionRate returns Rejections/Calls, or 0 when there were no
+// calls yet.
+func (s MacroFuncStat) RejectionRate() float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+	return float64(s.Rejections) / float64(s.Calls)
+}
+
+type funcStats struct {
+	mu         sync.Mutex
+	calls      uint64
+	rejections uint64
+	ewma       time.Duration
+	decay      float64
+	slow       bool
+}
+
+type statKey struct {
+	macro, fn string
+}
+
+var (
+	statsMu sync.Mutex
+	stats   = map[statKey]*funcStats{}
+
+	onSlowEvaluator func(macro, funcName string, avg time.Duration)
+	slowThreshold   time.Duration
+)
+
+// OnSlowEvaluator registers fn to be called, after warmup, every time a
+// tracked evaluator's EWMA latency crosses threshold, i.e. goes from
+// below threshold to at or above it. Registering again
+// replaces the previously registered callback; pass a nil fn to disable it.
+func OnSlowEvaluator(threshold time.Duration, fn func(macro, funcName string, avg time.Duration)) {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	slowThreshold = threshold
+	onSlowEvaluator = fn
+}
+
+func statFor(macro, fn string) *funcStats {
+	key := statKey{macro, fn}
+
+	statsMu.Lock()
+	defer statsMu.Unlock()
+
+	s, ok := stats[key]
+	if !ok {
+		s = &funcStats{decay: ewmaDecay}
+		stats[key] = s
+	}
+	return s
+}
+
+// TrackEvaluator wraps eval so each call updates the EWMA latency, call
+// count and rejection count tracked under (macro, funcName). funcName
+// should be "" for a macro's base evaluator.
+//
+// The StatsEnabled check happens on every call, not once here at wrap
+// time: Alphabetical's and File's base evaluators are wrapped during
+// package initialization, before an importing
+// application has had any chance to set StatsEnabled -- gating at wrap
+// time would've locked those two (the motivating case for this whole
+// feature) out of tracking forever.
+func TrackEvaluator(macro, funcName string, eval EvaluatorFunc) EvaluatorFunc {
+	if eval == nil {
+		return eval
+	}
+
+	return func(paramValue string) bool {
+		if !StatsEnabled {
+			return eval(paramValue)
+		}
+
+		s := statFor(macro, funcName)
+
+		start := time.Now()
+		ok := eval(paramValue)
+		elapsed := time.Since(start)
+
+		s.mu.Lock()
+		s.calls++
+		if !ok {
+			s.rejections++
+		}
+		if s.calls <= warmupSamples {
+			s.ewma += (elapsed - s.ewma) / time.Duration(s.calls)
+		} else {
+			s.ewma = time.Duration(s.decay*float64(elapsed) + (1-s.decay)*float64(s.ewma))
+		}
+		avg, calls := s.ewma, s.calls
+		s.mu.Unlock()
+
+		if calls > warmupSamples {
+			statsMu.Lock()
+			fn, threshold := onSlowEvaluator, slowThreshold
+			statsMu.Unlock()
+
+			s.mu.Lock()
+			wasSlow := s.slow
+			s.slow = threshold > 0 && avg >= threshold
+			crossed := s.slow && !wasSlow
+			s.mu.Unlock()
+
+			if fn != nil && crossed {
+				fn(macro, funcName, avg)
+			}
+		}
+
+		return ok
+	}
+}
+
+// Stats returns the call statistics collected so far for this macro's
+// tracked evaluators (its base evaluator, under Func == "", and any
+// func wrapped with TrackEvaluator).
+func (m *Macro) Stats() []MacroFuncStat {
+	indent := m.Indent()
+
+	statsMu.Lock()
+	defer statsMu.Unlock()
+
+	var out []MacroFuncStat
+	for key, s := range stats {
+		if key.macro != indent {
+			continue
+		}
+
+		s.mu.Lock()
+		out = append(out, MacroFuncStat{
+			Macro:      key.macro,
+			Func:       key.fn,
+			Calls:      s.calls,
+			Rejections: s.rejections,
+			EWMA:       s.ewma,
+		})
+		s.mu.Unlock()
+	}
+
+	return out
+}
+
+// SlowerThan returns the stats of every tracked evaluator, across all
+// macros in ms, whose EWMA latency is at or above d.
+func (ms *Macros) SlowerThan(d time.Duration) []MacroFuncStat {
+	var out []MacroFuncStat
+	for _, m := range *ms {
+		for _, stat := range m.Stats() {
+			if stat.EWMA >= d {
+				out = append(out, stat)
+			}
+		}
+	}
+	return out
+}
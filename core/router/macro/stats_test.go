@@ -0,0 +1,120 @@
+package macro
+
+import "testing"
+
+func TestTrackEvaluatorNoopWhenDisabled(t *testing.T) {
+	StatsEnabled = false
+	defer func() { StatsEnabled = false }()
+
+	const macroName = "stats_test_disabled"
+
+	calls := 0
+	tracked := TrackEvaluator(macroName, "", func(string) bool {
+		calls++
+		return true
+	})
+
+	for i := 0; i < 3; i++ {
+		tracked("x")
+	}
+
+	if calls != 3 {
+		t.Fatalf("expected the wrapped evaluator to still run 3 times, got %d", calls)
+	}
+
+	s := statFor(macroName, "")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.calls != 0 {
+		t.Fatalf("expected no calls recorded while StatsEnabled is false, got %d", s.calls)
+	}
+}
+
+func TestTrackEvaluatorCountsCallsPastWarmup(t *testing.T) {
+	StatsEnabled = true
+	defer func() { StatsEnabled = false }()
+
+	const macroName = "stats_test_warmup"
+	tracked := TrackEvaluator(macroName, "check", func(string) bool { return true })
+
+	for i := 0; i < warmupSamples+1; i++ {
+		tracked("x")
+	}
+
+	s := statFor(macroName, "check")
+	s.mu.Lock()
+	calls := s.calls
+	s.mu.Unlock()
+
+	if calls != uint64(warmupSamples+1) {
+		t.Fatalf("expected %d calls recorded, got %d", warmupSamples+1, calls)
+	}
+}
+
+func TestTrackEvaluatorRejectionCount(t *testing.T) {
+	StatsEnabled = true
+	defer func() { StatsEnabled = false }()
+
+	const macroName = "stats_test_rejections"
+	tracked := TrackEvaluator(macroName, "", func(paramValue string) bool {
+		return paramValue == "ok"
+	})
+
+	tracked("ok")
+	tracked("no")
+	tracked("no")
+
+	s := statFor(macroName, "")
+	s.mu.Lock()
+	calls, rejections := s.calls, s.rejections
+	s.mu.Unlock()
+
+	if calls != 3 || rejections != 2 {
+		t.Fatalf("expected 3 calls / 2 rejections, got %d calls / %d rejections", calls, rejections)
+	}
+}
+
+func TestSetEWMADecay(t *testing.T) {
+	SetEWMADecay(0.5)
+	defer SetEWMADecay(0)
+
+	const macroName = "stats_test_decay"
+	s := statFor(macroName, "")
+	s.mu.Lock()
+	decay := s.decay
+	s.mu.Unlock()
+
+	if decay != 0.5 {
+		t.Fatalf("expected a newly created funcStats to pick up the overridden decay, got %v, want 0.5", decay)
+	}
+
+	SetEWMADecay(0)
+	s2 := statFor("stats_test_decay_reset", "")
+	s2.mu.Lock()
+	decay2 := s2.decay
+	s2.mu.Unlock()
+
+	if decay2 != defaultEWMADecay {
+		t.Fatalf("expected SetEWMADecay(0) to restore the default, got %v, want %v", decay2, defaultEWMADecay)
+	}
+}
+
+func TestSlowerThanScansOnlyGivenMacros(t *testing.T) {
+	StatsEnabled = true
+	defer func() { StatsEnabled = false }()
+
+	const macroName = "stats_test_slowerthan"
+	s := statFor(macroName, "")
+	s.mu.Lock()
+	s.calls = warmupSamples + 1
+	s.ewma = 50 // an arbitrarily "slow" recorded latency, in nanoseconds
+	s.mu.Unlock()
+
+	ms := Macros{}
+	found := ms.SlowerThan(1)
+	for _, stat := range found {
+		if stat.Macro == macroName {
+			t.Fatalf("SlowerThan should only scan the macros in ms, not the whole process-wide stats map; got %+v", stat)
+		}
+	}
+}